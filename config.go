@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Config is loaded from a JSON file (see configPathEnv) and controls which
+// pluggable backends the server uses.
+type Config struct {
+	Store   StoreConfig  `json:"store"`
+	Server  ServerConfig `json:"server"`
+	Publish []SinkConfig `json:"publish"`
+}
+
+// SinkConfig selects and configures one Publisher the outbox sends
+// ReceiptProcessed events to.
+type SinkConfig struct {
+	// Driver is one of "webhook", "nats", "kafka".
+	Driver string `json:"driver"`
+
+	// URL is the webhook endpoint ("webhook") or the NATS server URL
+	// ("nats").
+	URL string `json:"url"`
+
+	// Subject is the NATS subject to publish on ("nats" only).
+	Subject string `json:"subject"`
+
+	// Brokers lists Kafka broker addresses ("kafka" only).
+	Brokers []string `json:"brokers"`
+
+	// Topic is the Kafka topic to publish to ("kafka" only).
+	Topic string `json:"topic"`
+}
+
+// ServerConfig controls the timeouts on the REST http.Server.
+type ServerConfig struct {
+	// Durations are strings parsed with time.ParseDuration, e.g. "15s".
+	// An empty or unparseable value falls back to the default below.
+	ReadTimeout  string `json:"readTimeout"`
+	WriteTimeout string `json:"writeTimeout"`
+	IdleTimeout  string `json:"idleTimeout"`
+}
+
+const (
+	defaultReadTimeout  = 5 * time.Second
+	defaultWriteTimeout = 10 * time.Second
+	defaultIdleTimeout  = 120 * time.Second
+)
+
+// timeouts parses ServerConfig's duration strings, falling back to the
+// package defaults for anything empty or invalid.
+func (c ServerConfig) timeouts() (read, write, idle time.Duration) {
+	read = parseDurationOrDefault(c.ReadTimeout, defaultReadTimeout)
+	write = parseDurationOrDefault(c.WriteTimeout, defaultWriteTimeout)
+	idle = parseDurationOrDefault(c.IdleTimeout, defaultIdleTimeout)
+	return
+}
+
+func parseDurationOrDefault(s string, def time.Duration) time.Duration {
+	if s == "" {
+		return def
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return def
+	}
+	return d
+}
+
+// StoreConfig selects and configures the Store implementation.
+type StoreConfig struct {
+	// Driver is one of "memory", "bolt", "sqlite". Defaults to "memory".
+	Driver string `json:"driver"`
+
+	// DSN is the driver-specific connection string: a file path for
+	// "bolt"/"sqlite", unused for "memory".
+	DSN string `json:"dsn"`
+}
+
+// configPathEnv names the environment variable that points at the config
+// file. If unset, loadConfig falls back to defaultConfig.
+const configPathEnv = "FETCH_CONFIG"
+
+func defaultConfig() Config {
+	return Config{Store: StoreConfig{Driver: "memory"}}
+}
+
+// loadConfig reads the config file named by FETCH_CONFIG, if set, and
+// returns defaultConfig otherwise.
+func loadConfig() (Config, error) {
+	path := os.Getenv(configPathEnv)
+	if path == "" {
+		return defaultConfig(), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("read config %s: %w", path, err)
+	}
+
+	cfg := defaultConfig()
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// newStore builds the Store selected by cfg.
+func newStore(cfg StoreConfig) (Store, error) {
+	switch cfg.Driver {
+	case "", "memory":
+		return newMemoryStore(), nil
+	case "bolt":
+		return newBoltStore(cfg.DSN)
+	case "sqlite":
+		return newSQLiteStore(cfg.DSN)
+	default:
+		return nil, fmt.Errorf("unknown store driver %q", cfg.Driver)
+	}
+}
+
+// newPublishers builds one Publisher per configured sink.
+func newPublishers(cfgs []SinkConfig) ([]Publisher, error) {
+	publishers := make([]Publisher, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		switch cfg.Driver {
+		case "webhook":
+			publishers = append(publishers, newWebhookPublisher(cfg.URL))
+		case "nats":
+			p, err := newNATSPublisher(cfg.URL, cfg.Subject)
+			if err != nil {
+				return nil, err
+			}
+			publishers = append(publishers, p)
+		case "kafka":
+			publishers = append(publishers, newKafkaPublisher(cfg.Brokers, cfg.Topic))
+		default:
+			return nil, fmt.Errorf("unknown publish sink driver %q", cfg.Driver)
+		}
+	}
+	return publishers, nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// ReceiptProcessed is published after a receipt has been scored and saved,
+// so downstream systems (loyalty, fraud, ...) can react without polling
+// /receipts/{id}/points.
+type ReceiptProcessed struct {
+	ID        string    `json:"id"`
+	Retailer  string    `json:"retailer"`
+	Total     float64   `json:"total"`
+	Points    int       `json:"points"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Publisher sends a ReceiptProcessed event to one sink (webhook, NATS,
+// Kafka, ...). Implementations should treat ctx as a per-attempt deadline;
+// retrying is the outbox's job, not the Publisher's.
+type Publisher interface {
+	Publish(ctx context.Context, event ReceiptProcessed) error
+}
+
+// closablePublisher is implemented by Publishers that hold a long-lived
+// connection worth draining on shutdown (natsPublisher, kafkaPublisher).
+// It's a separate interface rather than part of Publisher because the
+// webhook publisher is stateless and has nothing to close.
+type closablePublisher interface {
+	Close() error
+}
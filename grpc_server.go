@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// receiptServer implements ReceiptServiceServer by calling the same
+// calculatePoints/store code path the REST handlers use, so the two APIs
+// can never disagree on how a receipt is scored.
+type receiptServer struct {
+	UnimplementedReceiptServiceServer
+}
+
+func (s *receiptServer) Process(ctx context.Context, receipt *Receipt) (*ProcessResponse, error) {
+	if fieldErrs := validateReceipt(receipt); len(fieldErrs) > 0 {
+		return nil, status.Error(codes.InvalidArgument, formatFieldErrors(fieldErrs))
+	}
+
+	id := uuid.New().String()
+
+	breakdown, err := calculatePoints(ctx, *receipt, centsFromFloat(receipt.Total))
+	if err != nil {
+		return nil, status.Errorf(codes.Canceled, "%v", err)
+	}
+
+	if err := store.Save(ctx, id, *receipt, breakdown); err != nil {
+		return nil, err
+	}
+	recordReceiptMetrics(*receipt, breakdown)
+
+	events.enqueue(ReceiptProcessed{
+		ID:        id,
+		Retailer:  receipt.Retailer,
+		Total:     receipt.Total,
+		Points:    breakdown.Total,
+		Timestamp: time.Now(),
+	})
+
+	return &ProcessResponse{Id: id}, nil
+}
+
+func (s *receiptServer) GetPoints(ctx context.Context, req *PointsRequest) (*PointsResponse, error) {
+	points, found, err := store.GetPoints(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, status.Errorf(codes.NotFound, "receipt %s not found", req.Id)
+	}
+
+	return &PointsResponse{Points: int32(points)}, nil
+}
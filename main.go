@@ -1,303 +1,396 @@
 package main
 
 import (
-    "encoding/json"
-    "fmt"
-    "log"
-    "net/http"
-    "github.com/google/uuid"
-    "strings"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc"
 )
 
-var receipts = make(map[string]int)
+// store is the configured Store backend, set up in main() before the HTTP
+// server starts listening.
+var store Store
 
-type Receipt struct {
-    Retailer     string  json:"retailer"
-    Total        float64 json:"total"
-    PurchaseDate string  json:"purchaseDate"
-    PurchaseTime string  json:"purchaseTime"
-    Items        []Item  json:"items"
-}
+// events delivers ReceiptProcessed notifications to the configured
+// publisher sinks. It is set up in main() alongside store.
+var events *outbox
 
-type Item struct {
-    Description string  json:"description"
-    Price       float64 json:"price"
-}
+// Receipt, Item, ProcessResponse and PointsResponse are now defined in
+// receipt.pb.go to match receipt.proto, so the REST handlers below and the
+// gRPC server in grpc_server.go score and store identical types.
 
-type ProcessResponse struct {
-    ID string json:"id"
+func main() {
+	// Load config and set up the storage backend it selects
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Fatalf("load config: %v", err)
+	}
+	store, err = newStore(cfg.Store)
+	if err != nil {
+		log.Fatalf("set up store: %v", err)
+	}
+
+	publishers, err := newPublishers(cfg.Publish)
+	if err != nil {
+		log.Fatalf("set up publishers: %v", err)
+	}
+	events = newOutbox(publishers)
+
+	// Cancel on SIGINT/SIGTERM so both servers below can drain in-flight
+	// requests instead of being killed mid-response.
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/receipts/process", processReceiptHandler)
+	mux.HandleFunc("/receipts", listReceiptsHandler)
+	mux.HandleFunc("/receipts/", getReceiptHandler)
+
+	// Register the receipt-scoring collector and expose it for scraping
+	prometheus.MustRegister(metrics)
+	mux.Handle("/metrics", promhttp.Handler())
+
+	readTimeout, writeTimeout, idleTimeout := cfg.Server.timeouts()
+	httpServer := &http.Server{
+		Addr:         ":8080",
+		Handler:      mux,
+		ReadTimeout:  readTimeout,
+		WriteTimeout: writeTimeout,
+		IdleTimeout:  idleTimeout,
+	}
+
+	grpcServer := grpc.NewServer()
+	RegisterReceiptServiceServer(grpcServer, &receiptServer{})
+
+	// Start the gRPC server on :9090 in the background; it shares
+	// calculatePoints and store with the REST handlers above.
+	go serveGRPC(grpcServer, ":9090")
+
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		<-ctx.Done()
+		fmt.Println("Shutting down...")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := httpServer.Shutdown(shutdownCtx); err != nil {
+			log.Println("http shutdown:", err)
+		}
+
+		// GracefulStop has no timeout of its own and blocks until every
+		// in-flight RPC finishes, so race it against shutdownCtx and fall
+		// back to a hard Stop if a slow call is still running when that
+		// expires.
+		gracefulDone := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(gracefulDone)
+		}()
+		select {
+		case <-gracefulDone:
+		case <-shutdownCtx.Done():
+			grpcServer.Stop()
+		}
+
+		// Both servers have stopped taking new requests, so nothing will
+		// enqueue further events or Store calls past this point.
+		if err := events.Close(); err != nil {
+			log.Println("outbox close:", err)
+		}
+		if err := store.Close(shutdownCtx); err != nil {
+			log.Println("store close:", err)
+		}
+	}()
+
+	// Log that the server is starting
+	fmt.Println("Server is running on port 8080...")
+
+	// Start the HTTP server and block until Shutdown is called above
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+
+	// ListenAndServe returns as soon as Shutdown begins, so wait for the
+	// drain above to finish before exiting.
+	<-shutdownDone
 }
 
-type PointsResponse struct {
-    Points int json:"points"
+// serveGRPC starts the ReceiptService gRPC server and blocks until it exits.
+func serveGRPC(s *grpc.Server, addr string) {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("grpc listen on %s: %v", addr, err)
+	}
+
+	fmt.Println("gRPC server is running on", addr)
+	if err := s.Serve(lis); err != nil {
+		log.Println("grpc serve:", err)
+	}
 }
 
-func main() {
-    // Set up the HTTP routes
-    http.HandleFunc("/receipts/process", processReceiptHandler)
-    http.HandleFunc("/receipts/", getPointsHandler)
-
-    // Log that the server is starting
-    fmt.Println("Server is running on port 8080...")
+// validationErrorResponse is the JSON body returned for a 400 from failed
+// validate(), in place of the old opaque "Invalid input" text.
+type validationErrorResponse struct {
+	Errors []FieldError `json:"errors"`
+}
 
-    // Start the HTTP server and block execution
-    log.Fatal(http.ListenAndServe(":8080", nil))
+func writeValidationErrors(w http.ResponseWriter, errs []FieldError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(validationErrorResponse{Errors: errs})
 }
 
 // Handler for processing receipts
 func processReceiptHandler(w http.ResponseWriter, r *http.Request) {
-    // Only accept POST requests
-    if r.Method != http.MethodPost {
-        http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
-        return
-    }
-
-    var receipt Receipt
-    // Decode JSON from the request body
-    if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
-        fmt.Println("Error decoding JSON:", err)  // Debugging line
-        http.Error(w, "Invalid input", http.StatusBadRequest)
-        return
-    }
-
-    // Generate a unique ID for the receipt
-    id := uuid.New().String()
-
-    // Calculate points for the receipt
-    points := calculatePoints(receipt)
-
-    // Store the points with the generated ID
-    receipts[id] = points
-
-    // Send the ID as the response
-    response := ProcessResponse{ID: id}
-    w.Header().Set("Content-Type", "application/json")
-    json.NewEncoder(w).Encode(response)
+	// Only accept POST requests
+	if r.Method != http.MethodPost {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var raw receiptJSON
+	// Decode JSON from the request body
+	if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+		fmt.Println("Error decoding JSON:", err) // Debugging line
+		http.Error(w, "Invalid input", http.StatusBadRequest)
+		return
+	}
+
+	if fieldErrs := validate(raw); len(fieldErrs) > 0 {
+		writeValidationErrors(w, fieldErrs)
+		return
+	}
+	receipt := raw.toReceipt()
+
+	// Generate a unique ID for the receipt
+	id := uuid.New().String()
+
+	// validate already confirmed raw.Total parses, so this can't fail
+	totalCents, _ := raw.totalCents()
+
+	// Calculate points for the receipt, along with a breakdown for metrics
+	breakdown, err := calculatePoints(r.Context(), receipt, totalCents)
+	if err != nil {
+		http.Error(w, "Request canceled", http.StatusRequestTimeout)
+		return
+	}
+
+	// Persist the receipt and its points under the generated ID
+	if err := store.Save(r.Context(), id, receipt, breakdown); err != nil {
+		fmt.Println("Error saving receipt:", err)
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	// Record the outcome for the /metrics endpoint
+	recordReceiptMetrics(receipt, breakdown)
+
+	// Notify downstream systems asynchronously; publish failures never fail
+	// this response.
+	events.enqueue(ReceiptProcessed{
+		ID:        id,
+		Retailer:  receipt.Retailer,
+		Total:     receipt.Total,
+		Points:    breakdown.Total,
+		Timestamp: time.Now(),
+	})
+
+	// Send the ID as the response
+	response := ProcessResponse{Id: id}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
-// Handler for retrieving points for a receipt by ID
-func getPointsHandler(w http.ResponseWriter, r *http.Request) {
-    // Ensure it's a GET request
-    if r.Method != http.MethodGet {
-        http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
-        return
-    }
-
-    // Extract the ID and check if URL contains "/points" at the end
-    pathParts := strings.Split(strings.TrimPrefix(r.URL.Path, "/receipts/"), "/")
-    if len(pathParts) != 2 || pathParts[1] != "points" {
-        http.Error(w, "Invalid endpoint", http.StatusNotFound)
-        return
-    }
-    id := pathParts[0]
-
-    // Look up points by ID
-    points, found := receipts[id]
-    if !found {
-        http.Error(w, "Receipt not found", http.StatusNotFound)
-        return
-    }
-
-    // Send the points as the response
-    response := PointsResponse{Points: points}
-    w.Header().Set("Content-Type", "application/json")
-    json.NewEncoder(w).Encode(response)
+// Handler for "/receipts/{id}" and "/receipts/{id}/points"
+func getReceiptHandler(w http.ResponseWriter, r *http.Request) {
+	// Ensure it's a GET request
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	pathParts := strings.Split(strings.TrimPrefix(r.URL.Path, "/receipts/"), "/")
+	switch len(pathParts) {
+	case 1:
+		getReceiptByID(w, r, pathParts[0])
+	case 2:
+		if pathParts[1] != "points" {
+			http.Error(w, "Invalid endpoint", http.StatusNotFound)
+			return
+		}
+		getPointsByID(w, r, pathParts[0])
+	default:
+		http.Error(w, "Invalid endpoint", http.StatusNotFound)
+	}
 }
 
-// Function to calculate the points for a receipt
-func calculatePoints(receipt Receipt) int {
-    points := 0
-
-    // 1. One point for every alphanumeric character in the retailer name
-    for _, c := range receipt.Retailer {
-        if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
-            points++
-        }
-    }
-
-    // 2. 50 points if the total is a round dollar amount with no cents
-    if receipt.Total == float64(int(receipt.Total)) {
-        points += 50
-    }
-
-    // 3. 25 points if the total is a multiple of 0.25
-    if int(receipt.Total*4)%4 == 0 {
-        points += 25
-    }
-
-    // 4. 5 points for every two items on the receipt
-    points += (len(receipt.Items) / 2) * 5
-
-    // 5. For each item, if the description length is a multiple of 3, multiply the price by 0.2 and round up
-    for _, item := range receipt.Items {
-        if len(item.Description)%3 == 0 {
-            points += int(item.Price*0.2 + 0.5) // rounding up
-        }
-    }
-
-    // 6. 6 points if the day in the purchase date is odd
-    var year, month, day int
-    fmt.Sscanf(receipt.PurchaseDate, "%d-%d-%d", &year, &month, &day)
-    if day%2 != 0 {
-        points += 6
-    }
-
-    // 7. 10 points if the time is after 2:00 PM and before 4:00 PM
-    var hour, minute int
-    fmt.Sscanf(receipt.PurchaseTime, "%d:%d", &hour, &minute)
-    if hour >= 14 && hour < 16 {
-        points += 10
-    }
-
-    return points
+// getPointsByID writes the PointsResponse for id, as before this endpoint
+// moved to a Store.
+func getPointsByID(w http.ResponseWriter, r *http.Request, id string) {
+	points, found, err := store.GetPoints(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+
+	response := PointsResponse{Points: int32(points)}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(response)
 }
 
+// getReceiptByID writes the original receipt plus its points breakdown.
+func getReceiptByID(w http.ResponseWriter, r *http.Request, id string) {
+	rec, found, err := store.Get(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+	if !found {
+		http.Error(w, "Receipt not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rec)
+}
 
+// listReceiptsHandler handles "GET /receipts?limit=&offset=", paginating
+// over everything the store has persisted.
+func listReceiptsHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit, err := parsePaginationParam(r, "limit", 20)
+	if err != nil {
+		http.Error(w, "Invalid limit", http.StatusBadRequest)
+		return
+	}
+	offset, err := parsePaginationParam(r, "offset", 0)
+	if err != nil {
+		http.Error(w, "Invalid offset", http.StatusBadRequest)
+		return
+	}
+
+	records, err := store.List(r.Context(), limit, offset)
+	if err != nil {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
 
+// parsePaginationParam reads an integer query parameter, falling back to def
+// when it's absent. It rejects negative values, since both limit and offset
+// are used directly as slice bounds in the Store implementations.
+func parsePaginationParam(r *http.Request, name string, def int) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, err
+	}
+	if v < 0 {
+		return 0, fmt.Errorf("%s must not be negative", name)
+	}
+	return v, nil
+}
 
+// PointsBreakdown records how many points each scoring rule contributed to a
+// single receipt, so callers (metrics, audits, ...) don't have to recompute
+// calculatePoints's internals to find out.
+type PointsBreakdown struct {
+	RetailerAlnum   int // rule 1: alphanumeric characters in the retailer name
+	RoundDollar     int // rule 2: round-dollar total
+	QuarterMultiple int // rule 3: total is a multiple of 0.25
+	ItemPairs       int // rule 4: points for every two items
+	ItemDescription int // rule 5: description-length bonus
+	OddDay          int // rule 6: odd purchase day
+	AfternoonWindow int // rule 7: 2pm-4pm purchase time
+	Total           int
+}
 
-
-// package main
-
-// import (
-//     "encoding/json"
-//     "fmt"
-//     "log"
-//     "net/http"
-//     "github.com/google/uuid"
-// )
-
-// var receipts = make(map[string]int)
-
-// type Receipt struct {
-//     Retailer     string  json:"retailer"
-//     Total        float64 json:"total"
-//     PurchaseDate string  json:"purchaseDate"
-//     PurchaseTime string  json:"purchaseTime"
-//     Items        []Item  json:"items"
-// }
-
-// type Item struct {
-//     Description string  json:"description"
-//     Price       float64 json:"price"
-// }
-
-// type ProcessResponse struct {
-//     ID string json:"id"
-// }
-
-// type PointsResponse struct {
-//     Points int json:"points"
-// }
-
-// func main() {
-//     // Set up the HTTP routes
-//     http.HandleFunc("/receipts/process", processReceiptHandler)
-//     http.HandleFunc("/receipts/", getPointsHandler)
-
-//     // Log that the server is starting
-//     fmt.Println("Server is running on port 8080...")
-
-//     // Start the HTTP server and block execution
-//     log.Fatal(http.ListenAndServe(":8080", nil))
-// }
-
-// // Handler for processing receipts
-// func processReceiptHandler(w http.ResponseWriter, r *http.Request) {
-//     // Only accept POST requests
-//     if r.Method != http.MethodPost {
-//         http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
-//         return
-//     }
-
-//     var receipt Receipt
-//     // Decode JSON from the request body
-//     if err := json.NewDecoder(r.Body).Decode(&receipt); err != nil {
-//         fmt.Println("Error decoding JSON:", err)  // Debugging line
-//         http.Error(w, "Invalid input", http.StatusBadRequest)
-//         return
-//     }
-
-//     // Generate a unique ID for the receipt
-//     id := uuid.New().String()
-
-//     // Calculate points for the receipt
-//     points := calculatePoints(receipt)
-
-//     // Store the points with the generated ID
-//     receipts[id] = points
-
-//     // Send the ID as the response
-//     response := ProcessResponse{ID: id}
-//     w.Header().Set("Content-Type", "application/json")
-//     json.NewEncoder(w).Encode(response)
-// }
-
-// // Handler for retrieving points for a receipt by ID
-// func getPointsHandler(w http.ResponseWriter, r *http.Request) {
-//     // Ensure it's a GET request
-//     if r.Method != http.MethodGet {
-//         http.Error(w, "Invalid method", http.StatusMethodNotAllowed)
-//         return
-//     }
-
-//     id := r.URL.Path[len("/receipts/"):] // Get the ID from the URL path
-//     points, found := receipts[id]
-//     if !found {
-//         http.Error(w, "Receipt not found", http.StatusNotFound)
-//         return
-//     }
-
-//     // Send the points as the response
-//     response := PointsResponse{Points: points}
-//     w.Header().Set("Content-Type", "application/json")
-//     json.NewEncoder(w).Encode(response)
-// }
-
-// // Function to calculate the points for a receipt
-// func calculatePoints(receipt Receipt) int {
-//     points := 0
-
-//     // 1. One point for every alphanumeric character in the retailer name
-//     for _, c := range receipt.Retailer {
-//         if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
-//             points++
-//         }
-//     }
-
-//     // 2. 50 points if the total is a round dollar amount with no cents
-//     if receipt.Total == float64(int(receipt.Total)) {
-//         points += 50
-//     }
-
-//     // 3. 25 points if the total is a multiple of 0.25
-//     if int(receipt.Total*4)%4 == 0 {
-//         points += 25
-//     }
-
-//     // 4. 5 points for every two items on the receipt
-//     points += (len(receipt.Items) / 2) * 5
-
-//     // 5. For each item, if the description length is a multiple of 3, multiply the price by 0.2 and round up
-//     for _, item := range receipt.Items {
-//         if len(item.Description)%3 == 0 {
-//             points += int(item.Price*0.2 + 0.5) // rounding up
-//         }
-//     }
-
-//     // 6. 6 points if the day in the purchase date is odd
-//     var year, month, day int
-//     fmt.Sscanf(receipt.PurchaseDate, "%d-%d-%d", &year, &month, &day)
-//     if day%2 != 0 {
-//         points += 6
-//     }
-
-//     // 7. 10 points if the time is after 2:00 PM and before 4:00 PM
-//     var hour, minute int
-//     fmt.Sscanf(receipt.PurchaseTime, "%d:%d", &hour, &minute)
-//     if hour >= 14 && hour < 16 {
-//         points += 10
-//     }
-
-//     return points
-// }
\ No newline at end of file
+// Function to calculate the points for a receipt. It takes ctx so a caller
+// can cancel before an expensive scan over a very large Items slice starts;
+// the work itself is in-memory and fast enough that it isn't checked again
+// partway through. totalCents is receipt.Total's exact cent value - the
+// round-dollar/quarter-multiple rules key off it instead of receipt.Total
+// itself so a caller with the original decimal string (see
+// receiptJSON.totalCents) doesn't lose that precision by going through
+// float64. A caller with only a float (e.g. the gRPC path) can fall back to
+// centsFromFloat.
+func calculatePoints(ctx context.Context, receipt Receipt, totalCents int64) (PointsBreakdown, error) {
+	if err := ctx.Err(); err != nil {
+		return PointsBreakdown{}, err
+	}
+
+	var b PointsBreakdown
+
+	// 1. One point for every alphanumeric character in the retailer name
+	for _, c := range receipt.Retailer {
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			b.RetailerAlnum++
+		}
+	}
+
+	// 2. 50 points if the total is a round dollar amount with no cents
+	if totalCents%100 == 0 {
+		b.RoundDollar = 50
+	}
+
+	// 3. 25 points if the total is a multiple of 0.25
+	if totalCents%25 == 0 {
+		b.QuarterMultiple = 25
+	}
+
+	// 4. 5 points for every two items on the receipt
+	b.ItemPairs = (len(receipt.Items) / 2) * 5
+
+	// 5. For each item, if the description length is a multiple of 3, multiply the price by 0.2 and round up
+	for _, item := range receipt.Items {
+		if len(item.Description)%3 == 0 {
+			b.ItemDescription += int(item.Price*0.2 + 0.5) // rounding up
+		}
+	}
+
+	// 6. 6 points if the day in the purchase date is odd
+	var year, month, day int
+	fmt.Sscanf(receipt.PurchaseDate, "%d-%d-%d", &year, &month, &day)
+	if day%2 != 0 {
+		b.OddDay = 6
+	}
+
+	// 7. 10 points if the time is after 2:00 PM and before 4:00 PM
+	var hour, minute int
+	fmt.Sscanf(receipt.PurchaseTime, "%d:%d", &hour, &minute)
+	if hour >= 14 && hour < 16 {
+		b.AfternoonWindow = 10
+	}
+
+	b.Total = b.RetailerAlnum + b.RoundDollar + b.QuarterMultiple + b.ItemPairs + b.ItemDescription + b.OddDay + b.AfternoonWindow
+
+	return b, nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// receiptCollector implements prometheus.Collector, following the same
+// wrap-child-collectors pattern used by the Helium blockchain exporter: it
+// owns a handful of standard prometheus metrics and simply delegates
+// Describe/Collect to them, rather than maintaining its own bookkeeping.
+type receiptCollector struct {
+	receiptsTotal      prometheus.Counter
+	pointsTotal        prometheus.Counter
+	receiptsByRetailer *prometheus.CounterVec
+	pointsByRetailer   *prometheus.CounterVec
+	pointsHistogram    prometheus.Histogram
+	ruleHits           *prometheus.CounterVec
+}
+
+func newReceiptCollector() *receiptCollector {
+	return &receiptCollector{
+		receiptsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fetch_receipts_processed_total",
+			Help: "Total number of receipts processed.",
+		}),
+		pointsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "fetch_points_awarded_total",
+			Help: "Total number of points awarded across all receipts.",
+		}),
+		receiptsByRetailer: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "fetch_receipts_by_retailer_total",
+			Help: "Number of receipts processed, per retailer.",
+		}, []string{"retailer"}),
+		pointsByRetailer: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "fetch_points_by_retailer_total",
+			Help: "Number of points awarded, per retailer.",
+		}, []string{"retailer"}),
+		pointsHistogram: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "fetch_points_per_receipt",
+			Help:    "Distribution of points awarded per receipt.",
+			Buckets: prometheus.LinearBuckets(0, 20, 10),
+		}),
+		ruleHits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "fetch_rule_hits_total",
+			Help: "Number of times each scoring rule contributed points to a receipt.",
+		}, []string{"rule"}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *receiptCollector) Describe(ch chan<- *prometheus.Desc) {
+	c.receiptsTotal.Describe(ch)
+	c.pointsTotal.Describe(ch)
+	c.receiptsByRetailer.Describe(ch)
+	c.pointsByRetailer.Describe(ch)
+	c.pointsHistogram.Describe(ch)
+	c.ruleHits.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *receiptCollector) Collect(ch chan<- prometheus.Metric) {
+	c.receiptsTotal.Collect(ch)
+	c.pointsTotal.Collect(ch)
+	c.receiptsByRetailer.Collect(ch)
+	c.pointsByRetailer.Collect(ch)
+	c.pointsHistogram.Collect(ch)
+	c.ruleHits.Collect(ch)
+}
+
+// observe records a single processed receipt and its rule breakdown. It is
+// called, via recordReceiptMetrics, from processReceiptHandler and the gRPC
+// Process method after calculatePoints has run.
+func (c *receiptCollector) observe(receipt Receipt, b PointsBreakdown) {
+	c.receiptsTotal.Inc()
+	c.pointsTotal.Add(float64(b.Total))
+	c.receiptsByRetailer.WithLabelValues(receipt.Retailer).Inc()
+	c.pointsByRetailer.WithLabelValues(receipt.Retailer).Add(float64(b.Total))
+	c.pointsHistogram.Observe(float64(b.Total))
+
+	if b.RetailerAlnum > 0 {
+		c.ruleHits.WithLabelValues("retailer-alnum").Inc()
+	}
+	if b.RoundDollar > 0 {
+		c.ruleHits.WithLabelValues("round-dollar").Inc()
+	}
+	if b.QuarterMultiple > 0 {
+		c.ruleHits.WithLabelValues("multiple-of-0.25").Inc()
+	}
+	if b.ItemPairs > 0 {
+		c.ruleHits.WithLabelValues("per-two-items").Inc()
+	}
+	if b.ItemDescription > 0 {
+		c.ruleHits.WithLabelValues("description-length").Inc()
+	}
+	if b.OddDay > 0 {
+		c.ruleHits.WithLabelValues("odd-day").Inc()
+	}
+	if b.AfternoonWindow > 0 {
+		c.ruleHits.WithLabelValues("afternoon-window").Inc()
+	}
+}
+
+// metrics is the process-wide collector used by the HTTP handlers to report
+// scoring outcomes. It is registered with the default prometheus registry in
+// main().
+var metrics = newReceiptCollector()
+
+// recordReceiptMetrics is a small indirection so handlers don't need to know
+// about the collector's internals.
+func recordReceiptMetrics(receipt Receipt, b PointsBreakdown) {
+	metrics.observe(receipt, b)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+)
+
+// outboxCapacity bounds how many un-published events can be queued before
+// new ones are dropped (and logged) rather than blocking the handler.
+const outboxCapacity = 256
+
+// outboxMaxAttempts bounds retries per publisher per event, so a sink that
+// is down doesn't retry forever.
+const outboxMaxAttempts = 5
+
+// outbox delivers ReceiptProcessed events to every configured Publisher
+// asynchronously. A failed publish is retried with backoff and, if still
+// failing after outboxMaxAttempts, logged and dropped - the HTTP response
+// to the client is never affected either way.
+type outbox struct {
+	publishers []Publisher
+	queue      chan ReceiptProcessed
+}
+
+func newOutbox(publishers []Publisher) *outbox {
+	ob := &outbox{
+		publishers: publishers,
+		queue:      make(chan ReceiptProcessed, outboxCapacity),
+	}
+	go ob.run()
+	return ob
+}
+
+// enqueue schedules event for delivery. It never blocks: a full queue drops
+// the event and logs it rather than slow down the caller.
+func (ob *outbox) enqueue(event ReceiptProcessed) {
+	if len(ob.publishers) == 0 {
+		return
+	}
+
+	select {
+	case ob.queue <- event:
+	default:
+		log.Printf("outbox full, dropping ReceiptProcessed event for %s", event.ID)
+	}
+}
+
+func (ob *outbox) run() {
+	for event := range ob.queue {
+		for _, p := range ob.publishers {
+			ob.publishWithRetry(p, event)
+		}
+	}
+}
+
+// Close closes every publisher that holds a long-lived connection worth
+// draining (NATS, Kafka); stateless publishers (webhook) have nothing to
+// close and are skipped. It doesn't wait for ob.queue to drain first - the
+// caller is expected to have stopped enqueuing by the time it calls Close.
+func (ob *outbox) Close() error {
+	var errs []error
+	for _, p := range ob.publishers {
+		if c, ok := p.(closablePublisher); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (ob *outbox) publishWithRetry(p Publisher, event ReceiptProcessed) {
+	backoff := 100 * time.Millisecond
+
+	for attempt := 1; attempt <= outboxMaxAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		err := p.Publish(ctx, event)
+		cancel()
+		if err == nil {
+			return
+		}
+
+		if attempt == outboxMaxAttempts {
+			log.Printf("giving up publishing receipt %s after %d attempts: %v", event.ID, attempt, err)
+			return
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
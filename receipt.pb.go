@@ -0,0 +1,160 @@
+// Hand-written to match receipt.proto's message shapes, in the style of an
+// older protoc-gen-go (pre protoreflect/protoimpl v2). No protoc toolchain
+// was available to generate this for real; if receipt.proto changes,
+// update this file by hand to match, or regenerate it with protoc and
+// protoc-gen-go and replace it outright.
+
+package main
+
+import (
+	proto "github.com/golang/protobuf/proto"
+)
+
+// Item is the protobuf counterpart of receipt.proto's Item
+// message. JSON tags are kept so it can also be used directly by the
+// encoding/json-based REST handlers.
+type Item struct {
+	Description string  `protobuf:"bytes,1,opt,name=description,proto3" json:"description"`
+	Price       float64 `protobuf:"fixed64,2,opt,name=price,proto3" json:"price"`
+
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Item) Reset()         { *m = Item{} }
+func (m *Item) String() string { return proto.CompactTextString(m) }
+func (*Item) ProtoMessage()    {}
+
+func (m *Item) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Item) GetPrice() float64 {
+	if m != nil {
+		return m.Price
+	}
+	return 0
+}
+
+// Receipt is the protobuf counterpart of receipt.proto's Receipt
+// message. It replaces the hand-written struct that used to live in
+// main.go, so the JSON HTTP handlers and the gRPC server validate and score
+// the exact same type.
+type Receipt struct {
+	Retailer     string  `protobuf:"bytes,1,opt,name=retailer,proto3" json:"retailer"`
+	Total        float64 `protobuf:"fixed64,2,opt,name=total,proto3" json:"total"`
+	PurchaseDate string  `protobuf:"bytes,3,opt,name=purchase_date,json=purchaseDate,proto3" json:"purchaseDate"`
+	PurchaseTime string  `protobuf:"bytes,4,opt,name=purchase_time,json=purchaseTime,proto3" json:"purchaseTime"`
+	Items        []*Item `protobuf:"bytes,5,rep,name=items,proto3" json:"items"`
+
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Receipt) Reset()         { *m = Receipt{} }
+func (m *Receipt) String() string { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()    {}
+
+func (m *Receipt) GetRetailer() string {
+	if m != nil {
+		return m.Retailer
+	}
+	return ""
+}
+
+func (m *Receipt) GetTotal() float64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+func (m *Receipt) GetPurchaseDate() string {
+	if m != nil {
+		return m.PurchaseDate
+	}
+	return ""
+}
+
+func (m *Receipt) GetPurchaseTime() string {
+	if m != nil {
+		return m.PurchaseTime
+	}
+	return ""
+}
+
+func (m *Receipt) GetItems() []*Item {
+	if m != nil {
+		return m.Items
+	}
+	return nil
+}
+
+// ProcessResponse is the protobuf counterpart of
+// receipt.proto's ProcessResponse message.
+type ProcessResponse struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id"`
+
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProcessResponse) Reset()         { *m = ProcessResponse{} }
+func (m *ProcessResponse) String() string { return proto.CompactTextString(m) }
+func (*ProcessResponse) ProtoMessage()    {}
+
+func (m *ProcessResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// PointsRequest is the protobuf counterpart of receipt.proto's
+// PointsRequest message. It has no REST equivalent; the id is taken from
+// the URL path there instead.
+type PointsRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id"`
+
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PointsRequest) Reset()         { *m = PointsRequest{} }
+func (m *PointsRequest) String() string { return proto.CompactTextString(m) }
+func (*PointsRequest) ProtoMessage()    {}
+
+func (m *PointsRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+// PointsResponse is the protobuf counterpart of receipt.proto's
+// PointsResponse message.
+type PointsResponse struct {
+	Points int32 `protobuf:"varint,1,opt,name=points,proto3" json:"points"`
+
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PointsResponse) Reset()         { *m = PointsResponse{} }
+func (m *PointsResponse) String() string { return proto.CompactTextString(m) }
+func (*PointsResponse) ProtoMessage()    {}
+
+func (m *PointsResponse) GetPoints() int32 {
+	if m != nil {
+		return m.Points
+	}
+	return 0
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotFound is the error for a receipt that does not exist. The Store
+// methods below do not return it; they report a missing id with found ==
+// false instead.
+var ErrNotFound = errors.New("receipt not found")
+
+// ReceiptRecord is what a Store persists and returns for a processed
+// receipt: the original input plus how its points were derived.
+type ReceiptRecord struct {
+	ID        string          `json:"id"`
+	Receipt   Receipt         `json:"receipt"`
+	Breakdown PointsBreakdown `json:"breakdown"`
+}
+
+// Store persists processed receipts and their awarded points. Implementations
+// must be safe for concurrent use, since they're called directly from HTTP
+// and gRPC handlers. Every method takes a context so a slow backend can
+// honor a caller's deadline or cancellation.
+type Store interface {
+	// Save persists a processed receipt under id, replacing any existing
+	// record with the same id.
+	Save(ctx context.Context, id string, r Receipt, b PointsBreakdown) error
+
+	// GetPoints returns the total points for id. found is false if no such
+	// receipt has been saved.
+	GetPoints(ctx context.Context, id string) (points int, found bool, err error)
+
+	// Get returns the full record for id, including the original receipt
+	// and its points breakdown.
+	Get(ctx context.Context, id string) (ReceiptRecord, bool, error)
+
+	// List returns up to limit records, skipping the first offset, ordered
+	// by the time they were saved. It is used to page through /receipts.
+	List(ctx context.Context, limit, offset int) ([]ReceiptRecord, error)
+
+	// Close releases any resources the Store holds (file locks, database
+	// handles, ...). It is called once, during shutdown.
+	Close(ctx context.Context) error
+}
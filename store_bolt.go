@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	boltBucket = []byte("receipts")
+
+	// boltOrderBucket maps a big-endian uint64 sequence number to the id
+	// saved at that sequence, so List can page in save order without
+	// relying on bbolt's lexicographic key order over ids (which is
+	// effectively random relative to insertion order).
+	boltOrderBucket = []byte("receipts_order")
+)
+
+// boltStore persists receipts in a single-file BoltDB database. bbolt
+// transactions already serialize writers and allow concurrent readers, so
+// this needs no extra locking of its own.
+type boltStore struct {
+	db *bolt.DB
+}
+
+func newBoltStore(path string) (*boltStore, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, fmt.Errorf("open bolt store: %w", err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(boltBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(boltOrderBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create bolt bucket: %w", err)
+	}
+
+	return &boltStore{db: db}, nil
+}
+
+func (s *boltStore) Save(ctx context.Context, id string, r Receipt, b PointsBreakdown) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	rec := ReceiptRecord{ID: id, Receipt: r, Breakdown: b}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal receipt record: %w", err)
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		receipts := tx.Bucket(boltBucket)
+
+		// Only assign a new sequence number the first time id is saved, so
+		// re-saving an existing receipt doesn't move it in List order.
+		if receipts.Get([]byte(id)) == nil {
+			order := tx.Bucket(boltOrderBucket)
+			seq, err := order.NextSequence()
+			if err != nil {
+				return fmt.Errorf("assign save sequence for %s: %w", id, err)
+			}
+			var key [8]byte
+			binary.BigEndian.PutUint64(key[:], seq)
+			if err := order.Put(key[:], []byte(id)); err != nil {
+				return fmt.Errorf("record save sequence for %s: %w", id, err)
+			}
+		}
+
+		return receipts.Put([]byte(id), data)
+	})
+}
+
+func (s *boltStore) Get(ctx context.Context, id string) (ReceiptRecord, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return ReceiptRecord{}, false, err
+	}
+
+	var rec ReceiptRecord
+	var found bool
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(boltBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &rec)
+	})
+	if err != nil {
+		return ReceiptRecord{}, false, fmt.Errorf("get receipt %s: %w", id, err)
+	}
+	return rec, found, nil
+}
+
+func (s *boltStore) GetPoints(ctx context.Context, id string) (int, bool, error) {
+	rec, found, err := s.Get(ctx, id)
+	if err != nil || !found {
+		return 0, found, err
+	}
+	return rec.Breakdown.Total, true, nil
+}
+
+func (s *boltStore) List(ctx context.Context, limit, offset int) ([]ReceiptRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	out := []ReceiptRecord{}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		receipts := tx.Bucket(boltBucket)
+		c := tx.Bucket(boltOrderBucket).Cursor()
+		i := 0
+		for _, id := c.First(); id != nil; _, id = c.Next() {
+			if i < offset {
+				i++
+				continue
+			}
+			if limit > 0 && len(out) >= limit {
+				break
+			}
+
+			data := receipts.Get(id)
+			if data == nil {
+				i++
+				continue
+			}
+			var rec ReceiptRecord
+			if err := json.Unmarshal(data, &rec); err != nil {
+				return fmt.Errorf("unmarshal receipt %s: %w", id, err)
+			}
+			out = append(out, rec)
+			i++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (s *boltStore) Close(ctx context.Context) error {
+	return s.db.Close()
+}
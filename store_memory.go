@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// memoryStore is the in-memory Store implementation. It replaces the old
+// package-level `receipts` map and adds the locking that map was missing:
+// handlers can call Save/Get/List concurrently without racing.
+type memoryStore struct {
+	mu      sync.RWMutex
+	records map[string]ReceiptRecord
+	order   []string // insertion order, for List pagination
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{
+		records: make(map[string]ReceiptRecord),
+	}
+}
+
+func (s *memoryStore) Save(ctx context.Context, id string, r Receipt, b PointsBreakdown) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.records[id]; !exists {
+		s.order = append(s.order, id)
+	}
+	s.records[id] = ReceiptRecord{ID: id, Receipt: r, Breakdown: b}
+	return nil
+}
+
+func (s *memoryStore) GetPoints(ctx context.Context, id string) (int, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, found := s.records[id]
+	if !found {
+		return 0, false, nil
+	}
+	return rec.Breakdown.Total, true, nil
+}
+
+func (s *memoryStore) Get(ctx context.Context, id string) (ReceiptRecord, bool, error) {
+	if err := ctx.Err(); err != nil {
+		return ReceiptRecord{}, false, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, found := s.records[id]
+	return rec, found, nil
+}
+
+func (s *memoryStore) List(ctx context.Context, limit, offset int) ([]ReceiptRecord, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if offset >= len(s.order) {
+		return []ReceiptRecord{}, nil
+	}
+
+	ids := s.order[offset:]
+	if limit > 0 && limit < len(ids) {
+		ids = ids[:limit]
+	}
+
+	out := make([]ReceiptRecord, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, s.records[id])
+	}
+	return out, nil
+}
+
+// Close is a no-op: memoryStore holds nothing beyond process memory.
+func (s *memoryStore) Close(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// sqliteStore persists receipts in a SQLite database via database/sql. The
+// schema keeps the receipt and breakdown as JSON blobs rather than
+// normalizing them into columns, since nothing here queries their fields
+// directly - only /receipts and /receipts/{id} read them back whole.
+//
+// Every method uses the *Context query variants, so a caller's deadline or
+// cancellation actually aborts the underlying SQL call instead of just
+// discarding its result.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func newSQLiteStore(dsn string) (*sqliteStore, error) {
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite store: %w", err)
+	}
+
+	_, err = db.Exec(`
+		CREATE TABLE IF NOT EXISTS receipts (
+			id            TEXT PRIMARY KEY,
+			receipt_json  TEXT NOT NULL,
+			points        INTEGER NOT NULL,
+			breakdown_json TEXT NOT NULL,
+			seq           INTEGER
+		)
+	`)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create receipts table: %w", err)
+	}
+
+	return &sqliteStore{db: db}, nil
+}
+
+func (s *sqliteStore) Save(ctx context.Context, id string, r Receipt, b PointsBreakdown) error {
+	receiptJSON, err := json.Marshal(r)
+	if err != nil {
+		return fmt.Errorf("marshal receipt: %w", err)
+	}
+	breakdownJSON, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Errorf("marshal breakdown: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `
+		INSERT INTO receipts (id, receipt_json, points, breakdown_json, seq)
+		VALUES (?, ?, ?, ?, (SELECT COALESCE(MAX(seq), 0) + 1 FROM receipts))
+		ON CONFLICT(id) DO UPDATE SET
+			receipt_json = excluded.receipt_json,
+			points = excluded.points,
+			breakdown_json = excluded.breakdown_json
+	`, id, string(receiptJSON), b.Total, string(breakdownJSON))
+	if err != nil {
+		return fmt.Errorf("save receipt %s: %w", id, err)
+	}
+	return nil
+}
+
+func (s *sqliteStore) GetPoints(ctx context.Context, id string) (int, bool, error) {
+	var points int
+	err := s.db.QueryRowContext(ctx, `SELECT points FROM receipts WHERE id = ?`, id).Scan(&points)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("get points for %s: %w", id, err)
+	}
+	return points, true, nil
+}
+
+func (s *sqliteStore) Get(ctx context.Context, id string) (ReceiptRecord, bool, error) {
+	var receiptJSON, breakdownJSON string
+	err := s.db.QueryRowContext(ctx, `SELECT receipt_json, breakdown_json FROM receipts WHERE id = ?`, id).
+		Scan(&receiptJSON, &breakdownJSON)
+	if err == sql.ErrNoRows {
+		return ReceiptRecord{}, false, nil
+	}
+	if err != nil {
+		return ReceiptRecord{}, false, fmt.Errorf("get receipt %s: %w", id, err)
+	}
+
+	rec := ReceiptRecord{ID: id}
+	if err := json.Unmarshal([]byte(receiptJSON), &rec.Receipt); err != nil {
+		return ReceiptRecord{}, false, fmt.Errorf("unmarshal receipt %s: %w", id, err)
+	}
+	if err := json.Unmarshal([]byte(breakdownJSON), &rec.Breakdown); err != nil {
+		return ReceiptRecord{}, false, fmt.Errorf("unmarshal breakdown %s: %w", id, err)
+	}
+	return rec, true, nil
+}
+
+func (s *sqliteStore) List(ctx context.Context, limit, offset int) ([]ReceiptRecord, error) {
+	if limit <= 0 {
+		limit = -1 // SQLite treats a negative LIMIT as "no limit"
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, receipt_json, breakdown_json FROM receipts
+		ORDER BY seq ASC
+		LIMIT ? OFFSET ?
+	`, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("list receipts: %w", err)
+	}
+	defer rows.Close()
+
+	out := []ReceiptRecord{}
+	for rows.Next() {
+		var rec ReceiptRecord
+		var receiptJSON, breakdownJSON string
+		if err := rows.Scan(&rec.ID, &receiptJSON, &breakdownJSON); err != nil {
+			return nil, fmt.Errorf("scan receipt row: %w", err)
+		}
+		if err := json.Unmarshal([]byte(receiptJSON), &rec.Receipt); err != nil {
+			return nil, fmt.Errorf("unmarshal receipt %s: %w", rec.ID, err)
+		}
+		if err := json.Unmarshal([]byte(breakdownJSON), &rec.Breakdown); err != nil {
+			return nil, fmt.Errorf("unmarshal breakdown %s: %w", rec.ID, err)
+		}
+		out = append(out, rec)
+	}
+	return out, rows.Err()
+}
+
+func (s *sqliteStore) Close(ctx context.Context) error {
+	return s.db.Close()
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError is one validation failure, reported to the client instead of
+// the old catch-all "Invalid input".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// receiptJSON mirrors the wire shape of a posted receipt, but keeps Total
+// and each item's Price as json.Number so "7.00" and "7.0" can still be
+// told apart after decoding - something a plain float64 field throws away.
+type receiptJSON struct {
+	Retailer     string      `json:"retailer"`
+	Total        json.Number `json:"total"`
+	PurchaseDate string      `json:"purchaseDate"`
+	PurchaseTime string      `json:"purchaseTime"`
+	Items        []itemJSON  `json:"items"`
+}
+
+type itemJSON struct {
+	Description string      `json:"description"`
+	Price       json.Number `json:"price"`
+}
+
+var (
+	retailerPattern = regexp.MustCompile(`^[\w\s\-&]+$`)
+	moneyPattern    = regexp.MustCompile(`^\d+\.\d{2}$`)
+)
+
+// validate checks r against the rules calculatePoints and the Store assume
+// already hold, and reports every violation rather than stopping at the
+// first one.
+func validate(r receiptJSON) []FieldError {
+	var errs []FieldError
+
+	if r.Retailer == "" || !retailerPattern.MatchString(r.Retailer) {
+		errs = append(errs, FieldError{"retailer", "must be non-empty and match ^[\\w\\s\\-&]+$"})
+	}
+
+	if !isValidDate(r.PurchaseDate) {
+		errs = append(errs, FieldError{"purchaseDate", "must be a valid date in YYYY-MM-DD form"})
+	}
+
+	if !isValidTime(r.PurchaseTime) {
+		errs = append(errs, FieldError{"purchaseTime", "must be a valid time in HH:MM (24h) form"})
+	}
+
+	totalCents, totalOK := parseCents(r.Total.String())
+	if !totalOK {
+		errs = append(errs, FieldError{"total", "must be a decimal amount with exactly two decimal places"})
+	}
+
+	if len(r.Items) == 0 {
+		errs = append(errs, FieldError{"items", "must contain at least one item"})
+	}
+
+	itemCentsSum := int64(0)
+	itemsOK := true
+	for i, item := range r.Items {
+		cents, ok := parseCents(item.Price.String())
+		if !ok {
+			errs = append(errs, FieldError{
+				Field:   itemFieldName(i, "price"),
+				Message: "must be a decimal amount with exactly two decimal places",
+			})
+			itemsOK = false
+			continue
+		}
+		itemCentsSum += cents
+	}
+
+	if totalOK && itemsOK && len(r.Items) > 0 {
+		diff := totalCents - itemCentsSum
+		if diff < -1 || diff > 1 {
+			errs = append(errs, FieldError{"total", "must match the sum of item prices within a cent"})
+		}
+	}
+
+	return errs
+}
+
+// validateReceipt runs the same structural checks as validate, adapted for
+// the already-typed *Receipt/*Item a gRPC client sends instead of the raw
+// JSON validate decodes. There's no decimal string left to check money
+// format against at that point, so this checks what the typed fields still
+// can: non-empty/well-formed retailer, valid date/time, at least one item,
+// and the items summing to the total within a cent. Unlike validate, it
+// also rejects an empty item description and a negative item price.
+func validateReceipt(r *Receipt) []FieldError {
+	var errs []FieldError
+
+	if r.Retailer == "" || !retailerPattern.MatchString(r.Retailer) {
+		errs = append(errs, FieldError{"retailer", "must be non-empty and match ^[\\w\\s\\-&]+$"})
+	}
+
+	if !isValidDate(r.PurchaseDate) {
+		errs = append(errs, FieldError{"purchaseDate", "must be a valid date in YYYY-MM-DD form"})
+	}
+
+	if !isValidTime(r.PurchaseTime) {
+		errs = append(errs, FieldError{"purchaseTime", "must be a valid time in HH:MM (24h) form"})
+	}
+
+	if len(r.Items) == 0 {
+		errs = append(errs, FieldError{"items", "must contain at least one item"})
+	}
+
+	itemCentsSum := int64(0)
+	for i, item := range r.Items {
+		if item.Description == "" {
+			errs = append(errs, FieldError{itemFieldName(i, "description"), "must be non-empty"})
+		}
+		if item.Price < 0 {
+			errs = append(errs, FieldError{itemFieldName(i, "price"), "must not be negative"})
+		}
+		itemCentsSum += centsFromFloat(item.Price)
+	}
+
+	if len(r.Items) > 0 {
+		diff := centsFromFloat(r.Total) - itemCentsSum
+		if diff < -1 || diff > 1 {
+			errs = append(errs, FieldError{"total", "must match the sum of item prices within a cent"})
+		}
+	}
+
+	return errs
+}
+
+// formatFieldErrors joins field errors into one message, for transports
+// like gRPC's status that have no structured place to put a []FieldError.
+func formatFieldErrors(errs []FieldError) string {
+	parts := make([]string, len(errs))
+	for i, e := range errs {
+		parts[i] = e.Field + ": " + e.Message
+	}
+	return strings.Join(parts, "; ")
+}
+
+// itemFieldName builds a field path like "items[1].price" for a FieldError.
+func itemFieldName(i int, field string) string {
+	return "items[" + strconv.Itoa(i) + "]." + field
+}
+
+// isValidDate reports whether s parses as YYYY-MM-DD and round-trips back
+// to itself. time.Parse already rejects out-of-range dates like 2023-02-30;
+// the round-trip additionally requires s to be in exactly that form.
+func isValidDate(s string) bool {
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return false
+	}
+	return t.Format("2006-01-02") == s
+}
+
+// isValidTime reports whether s parses as 24-hour HH:MM.
+func isValidTime(s string) bool {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return false
+	}
+	return t.Format("15:04") == s
+}
+
+// parseCents parses a money string formatted like moneyPattern into an
+// integer number of cents, avoiding float64 rounding error.
+func parseCents(s string) (int64, bool) {
+	if !moneyPattern.MatchString(s) {
+		return 0, false
+	}
+
+	parts := strings.SplitN(s, ".", 2)
+	whole, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	frac, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return whole*100 + frac, true
+}
+
+// totalCents returns r.Total as an exact integer number of cents, parsed
+// from the original decimal string rather than reconstructed from a
+// float64. Callers that have already run validate (and so know r.Total
+// parses) can ignore the bool.
+func (r receiptJSON) totalCents() (int64, bool) {
+	return parseCents(r.Total.String())
+}
+
+// centsFromFloat rounds f to the nearest cent. It's for callers that only
+// have a float64 total to begin with (the gRPC path's *Receipt has no
+// decimal string to parse exactly), so it's a fallback for totalCents, not
+// a replacement for it.
+func centsFromFloat(f float64) int64 {
+	return int64(math.Round(f * 100))
+}
+
+// toReceipt converts a validated receiptJSON into the Receipt type
+// calculatePoints and the Store operate on.
+func (r receiptJSON) toReceipt() Receipt {
+	items := make([]*Item, len(r.Items))
+	for i, item := range r.Items {
+		price, _ := item.Price.Float64()
+		items[i] = &Item{Description: item.Description, Price: price}
+	}
+
+	total, _ := r.Total.Float64()
+	return Receipt{
+		Retailer:     r.Retailer,
+		Total:        total,
+		PurchaseDate: r.PurchaseDate,
+		PurchaseTime: r.PurchaseTime,
+		Items:        items,
+	}
+}
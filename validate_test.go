@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func mustReceiptJSON(t *testing.T, body string) receiptJSON {
+	t.Helper()
+	var r receiptJSON
+	if err := json.Unmarshal([]byte(body), &r); err != nil {
+		t.Fatalf("unmarshal test fixture: %v", err)
+	}
+	return r
+}
+
+func TestValidate(t *testing.T) {
+	validBody := `{
+		"retailer": "Target",
+		"total": "35.35",
+		"purchaseDate": "2022-01-01",
+		"purchaseTime": "13:01",
+		"items": [
+			{"description": "Mountain Dew 12PK", "price": "6.49"},
+			{"description": "Emils Cheese Pizza", "price": "28.86"}
+		]
+	}`
+
+	tests := []struct {
+		name       string
+		body       string
+		wantFields []string // field names expected to have an error; nil means no errors
+	}{
+		{
+			name:       "valid receipt",
+			body:       validBody,
+			wantFields: nil,
+		},
+		{
+			name: "empty retailer",
+			body: `{"retailer":"","total":"10.00","purchaseDate":"2022-01-01","purchaseTime":"13:01",
+				"items":[{"description":"a","price":"10.00"}]}`,
+			wantFields: []string{"retailer"},
+		},
+		{
+			name: "retailer with disallowed characters",
+			body: `{"retailer":"Target!!","total":"10.00","purchaseDate":"2022-01-01","purchaseTime":"13:01",
+				"items":[{"description":"a","price":"10.00"}]}`,
+			wantFields: []string{"retailer"},
+		},
+		{
+			name: "invalid calendar date",
+			body: `{"retailer":"Target","total":"10.00","purchaseDate":"2022-02-30","purchaseTime":"13:01",
+				"items":[{"description":"a","price":"10.00"}]}`,
+			wantFields: []string{"purchaseDate"},
+		},
+		{
+			name: "malformed date",
+			body: `{"retailer":"Target","total":"10.00","purchaseDate":"01-01-2022","purchaseTime":"13:01",
+				"items":[{"description":"a","price":"10.00"}]}`,
+			wantFields: []string{"purchaseDate"},
+		},
+		{
+			name: "malformed time",
+			body: `{"retailer":"Target","total":"10.00","purchaseDate":"2022-01-01","purchaseTime":"1:01pm",
+				"items":[{"description":"a","price":"10.00"}]}`,
+			wantFields: []string{"purchaseTime"},
+		},
+		{
+			name: "total missing cents",
+			body: `{"retailer":"Target","total":"10","purchaseDate":"2022-01-01","purchaseTime":"13:01",
+				"items":[{"description":"a","price":"10.00"}]}`,
+			wantFields: []string{"total"},
+		},
+		{
+			name: "total with one decimal place",
+			body: `{"retailer":"Target","total":"10.0","purchaseDate":"2022-01-01","purchaseTime":"13:01",
+				"items":[{"description":"a","price":"10.00"}]}`,
+			wantFields: []string{"total"},
+		},
+		{
+			name: "no items",
+			body: `{"retailer":"Target","total":"10.00","purchaseDate":"2022-01-01","purchaseTime":"13:01",
+				"items":[]}`,
+			wantFields: []string{"items"},
+		},
+		{
+			name: "item price missing cents",
+			body: `{"retailer":"Target","total":"10.00","purchaseDate":"2022-01-01","purchaseTime":"13:01",
+				"items":[{"description":"a","price":"10"}]}`,
+			wantFields: []string{"items[0].price"},
+		},
+		{
+			name: "total does not match item sum",
+			body: `{"retailer":"Target","total":"10.00","purchaseDate":"2022-01-01","purchaseTime":"13:01",
+				"items":[{"description":"a","price":"1.00"}]}`,
+			wantFields: []string{"total"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := mustReceiptJSON(t, tt.body)
+			errs := validate(r)
+
+			gotFields := make(map[string]bool, len(errs))
+			for _, e := range errs {
+				gotFields[e.Field] = true
+			}
+
+			if len(tt.wantFields) == 0 && len(errs) != 0 {
+				t.Fatalf("validate() = %v, want no errors", errs)
+			}
+			for _, field := range tt.wantFields {
+				if !gotFields[field] {
+					t.Errorf("validate() missing expected error on field %q, got %v", field, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCents(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   int64
+		wantOK bool
+	}{
+		{"7.00", 700, true},
+		{"0.25", 25, true},
+		{"123.45", 12345, true},
+		{"7.0", 0, false},
+		{"7", 0, false},
+		{"seven", 0, false},
+		{"-1.00", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseCents(tt.in)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseCents(%q) = (%d, %v), want (%d, %v)", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}